@@ -0,0 +1,290 @@
+package helm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/layer5io/meshkit/encoding"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// ChartDependency is a single entry of a ChartsManifest, declaring one chart
+// that should be vendored by Sync.
+type ChartDependency struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Repository string `yaml:"repository"`
+	ValuesFile string `yaml:"valuesFile,omitempty"`
+}
+
+// ChartsManifest is the declarative list of charts a project depends on,
+// loaded from a single YAML file and vendored together via Sync.
+type ChartsManifest struct {
+	Charts []ChartDependency `yaml:"charts"`
+}
+
+// lockedChart is the resolved, persisted state of a single ChartDependency
+// after a successful Sync.
+type lockedChart struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Repository string `yaml:"repository"`
+	Digest     string `yaml:"digest"`
+}
+
+// ChartsLockfile is the on-disk record of resolved chart versions written by
+// Sync to "<vendorDir>/charts-lock.yaml". Its presence lets subsequent Sync
+// calls skip re-downloading charts whose manifest entry and vendored contents
+// haven't changed.
+type ChartsLockfile struct {
+	Charts []lockedChart `yaml:"charts"`
+}
+
+const lockfileName = "charts-lock.yaml"
+
+// LoadChartsManifest reads and parses the ChartsManifest at manifestPath.
+func LoadChartsManifest(manifestPath string) (*ChartsManifest, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, ErrLoadChartsManifest(err, manifestPath)
+	}
+
+	var manifest ChartsManifest
+	if err := encoding.Unmarshal(data, &manifest); err != nil {
+		return nil, ErrLoadChartsManifest(err, manifestPath)
+	}
+
+	return &manifest, nil
+}
+
+// Sync vendors every chart declared in the ChartsManifest at manifestPath
+// into vendorDir, one subdirectory per chart named after ChartDependency.Name,
+// and writes a lockfile recording the resolved version and SHA256 digest of
+// each vendored chart. Charts whose lockfile entry and vendored directory
+// digest still match the manifest are left untouched; everything else is
+// re-downloaded from its Repository, which may be an OCI reference
+// ("oci://...") or an HTTP(S) chart repository. Each vendored chart is then
+// rendered with DryRunHelmChartWithOptions into "<vendorDir>/<name>.yaml".
+func Sync(manifestPath, vendorDir string) error {
+	manifest, err := LoadChartsManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(vendorDir, 0755); err != nil {
+		return ErrSyncCharts(err, manifestPath)
+	}
+
+	lockPath := filepath.Join(vendorDir, lockfileName)
+	lock, err := loadLockfile(lockPath)
+	if err != nil {
+		return err
+	}
+
+	resolved := make([]lockedChart, 0, len(manifest.Charts))
+	for _, dep := range manifest.Charts {
+		chartDir := filepath.Join(vendorDir, dep.Name)
+		existing := lock.find(dep.Name)
+
+		if existing != nil && existing.Version == dep.Version && existing.Repository == dep.Repository && chartDirMatchesDigest(chartDir, existing.Digest) {
+			resolved = append(resolved, *existing)
+		} else {
+			digest, err := fetchChart(dep, chartDir)
+			if err != nil {
+				return ErrSyncCharts(err, dep.Name)
+			}
+
+			resolved = append(resolved, lockedChart{
+				Name:       dep.Name,
+				Version:    dep.Version,
+				Repository: dep.Repository,
+				Digest:     digest,
+			})
+		}
+
+		if err := renderVendoredChart(dep, chartDir, vendorDir); err != nil {
+			return ErrSyncCharts(err, dep.Name)
+		}
+	}
+
+	return writeLockfile(lockPath, ChartsLockfile{Charts: resolved})
+}
+
+func (l *ChartsLockfile) find(name string) *lockedChart {
+	if l == nil {
+		return nil
+	}
+	for i := range l.Charts {
+		if l.Charts[i].Name == name {
+			return &l.Charts[i]
+		}
+	}
+	return nil
+}
+
+func loadLockfile(lockPath string) (*ChartsLockfile, error) {
+	data, err := os.ReadFile(lockPath)
+	if os.IsNotExist(err) {
+		return &ChartsLockfile{}, nil
+	}
+	if err != nil {
+		return nil, ErrSyncCharts(err, lockPath)
+	}
+
+	var lock ChartsLockfile
+	if err := encoding.Unmarshal(data, &lock); err != nil {
+		return nil, ErrSyncCharts(err, lockPath)
+	}
+	return &lock, nil
+}
+
+func writeLockfile(lockPath string, lock ChartsLockfile) error {
+	data, err := encoding.Marshal(lock)
+	if err != nil {
+		return ErrSyncCharts(err, lockPath)
+	}
+	if err := os.WriteFile(lockPath, data, 0644); err != nil {
+		return ErrSyncCharts(err, lockPath)
+	}
+	return nil
+}
+
+// chartDirMatchesDigest reports whether the chart vendored at chartDir still
+// hashes to digest, so Sync can skip re-downloading it.
+func chartDirMatchesDigest(chartDir, digest string) bool {
+	if digest == "" {
+		return false
+	}
+	actual, err := digestDir(chartDir)
+	if err != nil {
+		return false
+	}
+	return actual == digest
+}
+
+// digestDir computes a deterministic SHA256 digest over the contents of
+// every regular file under dir.
+func digestDir(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := fmt.Fprintln(h, path); err != nil {
+			return err
+		}
+		_, err = io.Copy(h, f)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// fetchChart downloads dep into chartDir, replacing any existing contents,
+// and returns the resulting directory's digest.
+func fetchChart(dep ChartDependency, chartDir string) (string, error) {
+	parent := filepath.Dir(chartDir)
+	if err := os.RemoveAll(chartDir); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return "", err
+	}
+
+	archivePath, err := downloadChartArchive(dep)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(archivePath)
+
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		return "", err
+	}
+	defer archive.Close()
+
+	// chartutil.Expand untars the archive as "<parent>/<chart name>", which is
+	// why chartDir is required to be named after dep.Name.
+	if err := chartutil.Expand(parent, archive); err != nil {
+		return "", err
+	}
+
+	return digestDir(chartDir)
+}
+
+// renderVendoredChart dry-runs the chart vendored at chartDir, using
+// dep.ValuesFile when set, and writes the result to
+// "<vendorDir>/<dep.Name>.yaml" so Sync produces rendered manifests
+// alongside the vendored tree, not just the vendored archives.
+func renderVendoredChart(dep ChartDependency, chartDir, vendorDir string) error {
+	opts := DryRunOptions{}
+	if dep.ValuesFile != "" {
+		opts.ValuesFiles = []string{dep.ValuesFile}
+	}
+
+	f, err := os.Create(filepath.Join(vendorDir, dep.Name+".yaml"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return LoadHelmChartWithOptions(chartDir, f, "", opts)
+}
+
+// downloadChartArchive resolves dep.Repository/Name@Version to a local
+// archive path, supporting plain HTTP(S) chart repositories as well as OCI
+// registries (Repository of the form "oci://...").
+func downloadChartArchive(dep ChartDependency) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "meshkit-chart-*")
+	if err != nil {
+		return "", err
+	}
+
+	settings := cli.New()
+
+	if registry.IsOCI(dep.Repository) {
+		ref := fmt.Sprintf("%s/%s:%s", dep.Repository, dep.Name, dep.Version)
+		cl, err := registry.NewClient()
+		if err != nil {
+			return "", err
+		}
+		dl := downloader.ChartDownloader{
+			Out:            io.Discard,
+			Getters:        getter.All(settings),
+			RegistryClient: cl,
+		}
+		archivePath, _, err := dl.DownloadTo(ref, dep.Version, tmpDir)
+		return archivePath, err
+	}
+
+	chartURL, err := repo.FindChartInRepoURL(dep.Repository, dep.Name, dep.Version, "", "", "", getter.All(settings))
+	if err != nil {
+		return "", err
+	}
+
+	dl := downloader.ChartDownloader{
+		Out:     io.Discard,
+		Getters: getter.All(settings),
+	}
+	archivePath, _, err := dl.DownloadTo(chartURL, dep.Version, tmpDir)
+	return archivePath, err
+}
@@ -0,0 +1,54 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDigestDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("name: test\nversion: 1.0.0\n"), 0644); err != nil {
+		t.Fatalf("failed to write fixture file: %v", err)
+	}
+
+	first, err := digestDir(dir)
+	if err != nil {
+		t.Fatalf("digestDir returned error: %v", err)
+	}
+
+	second, err := digestDir(dir)
+	if err != nil {
+		t.Fatalf("digestDir returned error: %v", err)
+	}
+	if first != second {
+		t.Errorf("digestDir is not deterministic: %s != %s", first, second)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("name: test\nversion: 2.0.0\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite fixture file: %v", err)
+	}
+	changed, err := digestDir(dir)
+	if err != nil {
+		t.Fatalf("digestDir returned error: %v", err)
+	}
+	if changed == first {
+		t.Errorf("expected digest to change after file contents changed")
+	}
+}
+
+func TestChartsLockfileFind(t *testing.T) {
+	lock := ChartsLockfile{Charts: []lockedChart{
+		{Name: "nginx", Version: "1.0.0"},
+		{Name: "redis", Version: "2.0.0"},
+	}}
+
+	found := lock.find("redis")
+	if found == nil || found.Version != "2.0.0" {
+		t.Errorf("expected to find redis@2.0.0, got %+v", found)
+	}
+
+	if lock.find("missing") != nil {
+		t.Errorf("expected nil for an entry that doesn't exist")
+	}
+}
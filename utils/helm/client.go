@@ -0,0 +1,104 @@
+package helm
+
+import (
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// Client wraps a Helm action.Configuration bound to a real cluster, so
+// releases can be installed/upgraded/queried instead of only rendered.
+type Client struct {
+	cfg       *action.Configuration
+	namespace string
+}
+
+// NewClient builds a Client scoped to namespace. getter resolves the target
+// cluster, driver selects the release storage backend ("secret", "configmap",
+// or "memory"; defaults to "secret" when empty), and log may be nil.
+func NewClient(getter genericclioptions.RESTClientGetter, namespace, driver string, log action.DebugLog) (*Client, error) {
+	if log == nil {
+		log = func(string, ...interface{}) {}
+	}
+
+	cfg := new(action.Configuration)
+	if err := cfg.Init(getter, namespace, driver, log); err != nil {
+		return nil, ErrNewHelmClient(err, namespace)
+	}
+
+	return &Client{cfg: cfg, namespace: namespace}, nil
+}
+
+// Install installs c into the cluster under releaseName with the given
+// values, returning the resulting release.
+func (hc *Client) Install(releaseName string, c *chart.Chart, values map[string]interface{}) (*release.Release, error) {
+	act := action.NewInstall(hc.cfg)
+	act.ReleaseName = releaseName
+	act.Namespace = hc.namespace
+
+	rel, err := act.Run(c, values)
+	if err != nil {
+		return nil, ErrInstallHelmChart(err, releaseName)
+	}
+	return rel, nil
+}
+
+// Upgrade upgrades the release named releaseName to c with the given values.
+func (hc *Client) Upgrade(releaseName string, c *chart.Chart, values map[string]interface{}) (*release.Release, error) {
+	act := action.NewUpgrade(hc.cfg)
+	act.Namespace = hc.namespace
+
+	rel, err := act.Run(releaseName, c, values)
+	if err != nil {
+		return nil, ErrUpgradeHelmChart(err, releaseName)
+	}
+	return rel, nil
+}
+
+// Uninstall removes the release named releaseName from the cluster.
+func (hc *Client) Uninstall(releaseName string) (*release.UninstallReleaseResponse, error) {
+	act := action.NewUninstall(hc.cfg)
+
+	resp, err := act.Run(releaseName)
+	if err != nil {
+		return nil, ErrUninstallHelmChart(err, releaseName)
+	}
+	return resp, nil
+}
+
+// List returns every release tracked by the Client's storage driver.
+func (hc *Client) List() ([]*release.Release, error) {
+	act := action.NewList(hc.cfg)
+	act.All = true
+
+	releases, err := act.Run()
+	if err != nil {
+		return nil, ErrListHelmReleases(err)
+	}
+	return releases, nil
+}
+
+// Status returns the current status of the release named releaseName.
+func (hc *Client) Status(releaseName string) (*release.Release, error) {
+	act := action.NewStatus(hc.cfg)
+
+	rel, err := act.Run(releaseName)
+	if err != nil {
+		return nil, ErrHelmReleaseStatus(err, releaseName)
+	}
+	return rel, nil
+}
+
+// Rollback rolls the release named releaseName back to toVersion; a
+// toVersion of 0 rolls back to the immediately preceding revision, mirroring
+// `helm rollback <release>` with no explicit revision.
+func (hc *Client) Rollback(releaseName string, toVersion int) error {
+	act := action.NewRollback(hc.cfg)
+	act.Version = toVersion
+
+	if err := act.Run(releaseName); err != nil {
+		return ErrRollbackHelmRelease(err, releaseName)
+	}
+	return nil
+}
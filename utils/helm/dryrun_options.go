@@ -0,0 +1,171 @@
+package helm
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/layer5io/meshkit/utils"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/strvals"
+	"sigs.k8s.io/yaml"
+)
+
+// DryRunOptions customizes how DryRunHelmChartWithOptions and
+// ConvertToK8sManifestWithOptions render a chart: which values files to
+// merge, which individual values to override, and which release name and
+// namespace the rendering should pretend to target. Precedence follows
+// `helm install -f file1 -f file2 --set a=b --set-string c=d`: later
+// ValuesFiles override earlier ones, and SetValues/SetStringValues override
+// every values file.
+type DryRunOptions struct {
+	ValuesFiles     []string
+	SetValues       map[string]interface{}
+	SetStringValues map[string]string
+	ReleaseName     string
+	Namespace       string
+}
+
+// DryRunHelmChartWithOptions behaves like DryRunHelmChart but merges opts
+// into the chart's values before rendering.
+func DryRunHelmChartWithOptions(c *chart.Chart, kubernetesVersion string, opts DryRunOptions) ([]byte, error) {
+	values, err := mergeDryRunValues(opts)
+	if err != nil {
+		return nil, ErrDryRunHelmChart(err, c.Name())
+	}
+
+	actconfig := new(action.Configuration)
+	act := action.NewInstall(actconfig)
+	act.ReleaseName = c.Metadata.Name
+	act.Namespace = "default"
+	act.DryRun = true
+	act.IncludeCRDs = true
+	act.ClientOnly = true
+
+	if opts.ReleaseName != "" {
+		act.ReleaseName = opts.ReleaseName
+	}
+	if opts.Namespace != "" {
+		act.Namespace = opts.Namespace
+	}
+
+	kubeVersion := kubernetesVersion
+	if c.Metadata.KubeVersion != "" {
+		if extractedVersion := extractSemVer(c.Metadata.KubeVersion); extractedVersion != "" {
+			kubeVersion = extractedVersion
+		}
+	}
+	if kubeVersion != "" {
+		act.KubeVersion = &chartutil.KubeVersion{Version: kubeVersion}
+	}
+
+	rel, err := act.Run(c, values)
+	if err != nil {
+		return nil, ErrDryRunHelmChart(err, c.Name())
+	}
+
+	return []byte(rel.Manifest), nil
+}
+
+// ConvertToK8sManifestWithOptions behaves like ConvertToK8sManifest but
+// renders helm charts through DryRunHelmChartWithOptions using opts, so
+// callers can point at a values.yaml sitting next to the chart instead of
+// rendering with default values only.
+func ConvertToK8sManifestWithOptions(path, kubeVersion string, w io.Writer, opts DryRunOptions) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return utils.ErrReadDir(err, path)
+	}
+	helmChartPath := path
+	if !info.IsDir() {
+		helmChartPath, _ = strings.CutSuffix(path, filepath.Base(path))
+	}
+	if !IsHelmChart(helmChartPath) {
+		return ConvertToK8sManifest(path, kubeVersion, w)
+	}
+	return LoadHelmChartWithOptions(helmChartPath, w, kubeVersion, opts)
+}
+
+// LoadHelmChartWithOptions behaves like LoadHelmChart but renders the loaded
+// chart through DryRunHelmChartWithOptions using opts.
+func LoadHelmChartWithOptions(path string, w io.Writer, kubeVersion string, opts DryRunOptions) error {
+	dm := downloader.Manager{
+		Out:       w,
+		ChartPath: path,
+	}
+	c, err := loadHelmChartResolvingDeps(path, &dm)
+	if err != nil {
+		return ErrLoadHelmChart(err, path)
+	}
+
+	manifests, err := DryRunHelmChartWithOptions(c, kubeVersion, opts)
+	if err != nil {
+		return ErrLoadHelmChart(err, path)
+	}
+	manifests = cleanNilValues(manifests)
+
+	if _, err := w.Write(manifests); err != nil {
+		return ErrLoadHelmChart(err, path)
+	}
+	return nil
+}
+
+// mergeDryRunValues merges opts.ValuesFiles (in order, later files
+// overriding earlier ones) and then layers opts.SetValues and
+// opts.SetStringValues on top, mirroring the precedence of
+// `helm install -f ... --set ... --set-string ...`. The chart's own default
+// values are not merged in here; action.Install.Run already coalesces
+// whatever is returned with the chart's values.yaml.
+func mergeDryRunValues(opts DryRunOptions) (map[string]interface{}, error) {
+	base := map[string]interface{}{}
+
+	for _, valuesFile := range opts.ValuesFiles {
+		data, err := os.ReadFile(valuesFile)
+		if err != nil {
+			return nil, err
+		}
+		var fileValues map[string]interface{}
+		if err := yaml.Unmarshal(data, &fileValues); err != nil {
+			return nil, err
+		}
+		base = mergeValueMaps(base, fileValues)
+	}
+
+	for k, v := range opts.SetValues {
+		if err := strvals.ParseInto(fmt.Sprintf("%s=%v", k, v), base); err != nil {
+			return nil, err
+		}
+	}
+	for k, v := range opts.SetStringValues {
+		if err := strvals.ParseIntoString(fmt.Sprintf("%s=%s", k, v), base); err != nil {
+			return nil, err
+		}
+	}
+
+	return base, nil
+}
+
+// mergeValueMaps deep-merges override on top of base, with override values
+// taking precedence, the same semantics helm's own `-f` flag uses when
+// multiple values files are supplied.
+func mergeValueMaps(base, override map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range override {
+		if baseMap, ok := out[k].(map[string]interface{}); ok {
+			if overrideMap, ok := v.(map[string]interface{}); ok {
+				out[k] = mergeValueMaps(baseMap, overrideMap)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+package helm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeValueMaps(t *testing.T) {
+	base := map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": "nginx",
+			"tag":        "1.0",
+		},
+		"replicas": 1,
+	}
+	override := map[string]interface{}{
+		"image": map[string]interface{}{
+			"tag": "2.0",
+		},
+		"service": map[string]interface{}{
+			"type": "ClusterIP",
+		},
+	}
+
+	got := mergeValueMaps(base, override)
+
+	want := map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": "nginx",
+			"tag":        "2.0",
+		},
+		"replicas": 1,
+		"service": map[string]interface{}{
+			"type": "ClusterIP",
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeValueMaps() = %#v, want %#v", got, want)
+	}
+}
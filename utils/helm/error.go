@@ -0,0 +1,172 @@
+package helm
+
+import "github.com/layer5io/meshkit/errors"
+
+const (
+	ErrDryRunHelmChartCode       = "meshkit-11187"
+	ErrLoadHelmChartCode         = "meshkit-11188"
+	ErrLoadChartsManifestCode    = "meshkit-11328"
+	ErrSyncChartsCode            = "meshkit-11329"
+	ErrSplitManifestsCode        = "meshkit-11330"
+	ErrWalkManifestsCode         = "meshkit-11331"
+	ErrNewHelmClientCode         = "meshkit-11332"
+	ErrInstallHelmChartCode      = "meshkit-11333"
+	ErrUpgradeHelmChartCode      = "meshkit-11334"
+	ErrUninstallHelmChartCode    = "meshkit-11335"
+	ErrListHelmReleasesCode      = "meshkit-11336"
+	ErrHelmReleaseStatusCode     = "meshkit-11337"
+	ErrRollbackHelmReleaseCode   = "meshkit-11338"
+	ErrLocateChartCode           = "meshkit-11339"
+	ErrVerifyChartProvenanceCode = "meshkit-11340"
+)
+
+// ErrDryRunHelmChart is returned when DryRunHelmChart or
+// DryRunHelmChartWithOptions fails to render a chart's templates.
+func ErrDryRunHelmChart(err error, chartName string) error {
+	return errors.New(ErrDryRunHelmChartCode, errors.Alert,
+		[]string{"error dry running helm chart \"" + chartName + "\""},
+		[]string{err.Error()},
+		[]string{"the chart is corrupted or its template structure is not valid"},
+		[]string{"delete the chart, re-vendor it, and try again"})
+}
+
+// ErrLoadHelmChart is returned when LoadHelmChart or LoadHelmChartWithOptions
+// fails to load or render the chart at path.
+func ErrLoadHelmChart(err error, path string) error {
+	return errors.New(ErrLoadHelmChartCode, errors.Alert,
+		[]string{"error loading helm chart at \"" + path + "\""},
+		[]string{err.Error()},
+		[]string{"the chart does not exist at the specified path, or the path has insufficient read permissions"},
+		[]string{"provide the correct path to the chart directory/archive and ensure it is readable"})
+}
+
+// ErrLoadChartsManifest is returned when the ChartsManifest file passed to
+// Sync cannot be read or parsed.
+func ErrLoadChartsManifest(err error, manifestPath string) error {
+	return errors.New(ErrLoadChartsManifestCode, errors.Alert,
+		[]string{"error loading charts manifest"},
+		[]string{err.Error()},
+		[]string{"the charts manifest at " + manifestPath + " does not exist or is not valid YAML"},
+		[]string{"make sure the manifest path is correct and follows the ChartsManifest schema"})
+}
+
+// ErrSyncCharts is returned when Sync fails to vendor one of the charts
+// declared in a ChartsManifest, or to read/write the resulting lockfile.
+func ErrSyncCharts(err error, subject string) error {
+	return errors.New(ErrSyncChartsCode, errors.Alert,
+		[]string{"error syncing chart \"" + subject + "\""},
+		[]string{err.Error()},
+		[]string{"the chart repository is unreachable, the chart/version does not exist, or the vendor directory is not writable"},
+		[]string{"verify the chart repository and version in the manifest, and that the vendor directory is writable"})
+}
+
+// ErrSplitManifests is returned when a document in a rendered manifest
+// buffer cannot be parsed as YAML.
+func ErrSplitManifests(err error) error {
+	return errors.New(ErrSplitManifestsCode, errors.Alert,
+		[]string{"error splitting rendered manifests"},
+		[]string{err.Error()},
+		[]string{"one of the rendered documents is not valid YAML"},
+		[]string{"inspect the chart's templates for malformed output"})
+}
+
+// ErrWalkManifests is returned when WalkManifests fails to render chartPath
+// or when a caller-supplied callback returns an error.
+func ErrWalkManifests(err error, chartPath string) error {
+	return errors.New(ErrWalkManifestsCode, errors.Alert,
+		[]string{"error walking manifests for chart \"" + chartPath + "\""},
+		[]string{err.Error()},
+		[]string{"the chart failed to render, or the callback passed to WalkManifests returned an error"},
+		[]string{"check the chart renders successfully on its own and that the callback handles every resource kind it receives"})
+}
+
+// ErrNewHelmClient is returned when a Client cannot be initialized against
+// the target cluster.
+func ErrNewHelmClient(err error, namespace string) error {
+	return errors.New(ErrNewHelmClientCode, errors.Alert,
+		[]string{"error creating helm client for namespace \"" + namespace + "\""},
+		[]string{err.Error()},
+		[]string{"the supplied kubeconfig/REST config is invalid or the cluster is unreachable"},
+		[]string{"verify the cluster is reachable and the credentials used to build the RESTClientGetter are valid"})
+}
+
+// ErrInstallHelmChart is returned when Client.Install fails to install a
+// release onto the cluster.
+func ErrInstallHelmChart(err error, releaseName string) error {
+	return errors.New(ErrInstallHelmChartCode, errors.Alert,
+		[]string{"error installing release \"" + releaseName + "\""},
+		[]string{err.Error()},
+		[]string{"the chart or values are invalid, or the release already exists"},
+		[]string{"check the chart renders on its own and that the release name is not already in use"})
+}
+
+// ErrUpgradeHelmChart is returned when Client.Upgrade fails to upgrade an
+// existing release.
+func ErrUpgradeHelmChart(err error, releaseName string) error {
+	return errors.New(ErrUpgradeHelmChartCode, errors.Alert,
+		[]string{"error upgrading release \"" + releaseName + "\""},
+		[]string{err.Error()},
+		[]string{"the release does not exist, or the chart/values being upgraded to are invalid"},
+		[]string{"check the release exists and that the new chart renders on its own"})
+}
+
+// ErrUninstallHelmChart is returned when Client.Uninstall fails to remove a
+// release.
+func ErrUninstallHelmChart(err error, releaseName string) error {
+	return errors.New(ErrUninstallHelmChartCode, errors.Alert,
+		[]string{"error uninstalling release \"" + releaseName + "\""},
+		[]string{err.Error()},
+		[]string{"the release does not exist or some of its resources could not be deleted"},
+		[]string{"check the release exists and that the cluster credentials can delete its resources"})
+}
+
+// ErrListHelmReleases is returned when Client.List fails to enumerate
+// releases from the storage driver.
+func ErrListHelmReleases(err error) error {
+	return errors.New(ErrListHelmReleasesCode, errors.Alert,
+		[]string{"error listing helm releases"},
+		[]string{err.Error()},
+		[]string{"the storage driver's backing secrets/configmaps could not be listed"},
+		[]string{"check the cluster credentials can list secrets/configmaps in the target namespace"})
+}
+
+// ErrHelmReleaseStatus is returned when Client.Status fails to fetch the
+// status of a release.
+func ErrHelmReleaseStatus(err error, releaseName string) error {
+	return errors.New(ErrHelmReleaseStatusCode, errors.Alert,
+		[]string{"error fetching status for release \"" + releaseName + "\""},
+		[]string{err.Error()},
+		[]string{"the release does not exist"},
+		[]string{"check the release name and namespace are correct"})
+}
+
+// ErrRollbackHelmRelease is returned when Client.Rollback fails to roll a
+// release back to an earlier revision.
+func ErrRollbackHelmRelease(err error, releaseName string) error {
+	return errors.New(ErrRollbackHelmReleaseCode, errors.Alert,
+		[]string{"error rolling back release \"" + releaseName + "\""},
+		[]string{err.Error()},
+		[]string{"the release does not exist or the requested revision is not in its history"},
+		[]string{"check the release's revision history before retrying the rollback"})
+}
+
+// ErrLocateChart is returned when Locate fails to resolve or download a
+// chart reference.
+func ErrLocateChart(err error, ref string) error {
+	return errors.New(ErrLocateChartCode, errors.Alert,
+		[]string{"error locating chart \"" + ref + "\""},
+		[]string{err.Error()},
+		[]string{"the chart reference is malformed, the repository/registry is unreachable, or the chart/version does not exist"},
+		[]string{"verify the chart reference and that its repository or registry is reachable"})
+}
+
+// ErrVerifyChartProvenance is returned when a chart's detached OpenPGP
+// signature could not be downloaded or failed to verify against the
+// supplied keyring.
+func ErrVerifyChartProvenance(err error, ref string) error {
+	return errors.New(ErrVerifyChartProvenanceCode, errors.Alert,
+		[]string{"error verifying provenance for chart \"" + ref + "\""},
+		[]string{err.Error()},
+		[]string{"the chart has no \"*.prov\" file published alongside it, or its signature does not match the supplied keyring"},
+		[]string{"confirm the chart publisher signs releases and that the keyring passed to Verify contains their public key"})
+}
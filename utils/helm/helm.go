@@ -12,10 +12,8 @@ import (
 
 	"github.com/layer5io/meshkit/encoding"
 	"github.com/layer5io/meshkit/utils"
-	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
-	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/downloader"
 )
 
@@ -30,39 +28,11 @@ func extractSemVer(versionConstraint string) string {
 
 // DryRun a given helm chart to convert into k8s manifest
 func DryRunHelmChart(chart *chart.Chart, kubernetesVersion string) ([]byte, error) {
-	actconfig := new(action.Configuration)
-	act := action.NewInstall(actconfig)
-	act.ReleaseName = chart.Metadata.Name
-	act.Namespace = "default"
-	act.DryRun = true
-	act.IncludeCRDs = true
-	act.ClientOnly = true
-
-	kubeVersion := kubernetesVersion
-	if chart.Metadata.KubeVersion != "" {
-		extractedVersion := extractSemVer(chart.Metadata.KubeVersion)
-
-		if extractedVersion != "" {
-			kubeVersion = extractedVersion
-		}
-	}
-
-	if kubeVersion != "" {
-		act.KubeVersion = &chartutil.KubeVersion{
-			Version: kubeVersion,
-		}
-	}
-
-	rel, err := act.Run(chart, nil)
-	if err != nil {
-		return nil, ErrDryRunHelmChart(err, chart.Name())
-	}
-	var manifests bytes.Buffer
-	_, err = manifests.Write([]byte(strings.TrimSpace(rel.Manifest)))
+	manifests, err := DryRunHelmChartWithOptions(chart, kubernetesVersion, DryRunOptions{})
 	if err != nil {
-		return nil, ErrDryRunHelmChart(err, chart.Name())
+		return nil, err
 	}
-	return manifests.Bytes(), nil
+	return []byte(strings.TrimSpace(string(manifests))), nil
 }
 
 // Takes in the directory and converts HelmCharts/multiple manifests into a single K8s manifest
@@ -142,52 +112,65 @@ func IsHelmChart(dirPath string) bool {
 }
 
 func LoadHelmChart(path string, w io.Writer, kubeVersion string) error {
-	// Create a client for managing chart dependencies
 	dm := downloader.Manager{
 		Out:       w,
 		ChartPath: path,
 	}
+	chart, err := loadHelmChartResolvingDeps(path, &dm)
+	if err != nil {
+		return ErrLoadHelmChart(err, path)
+	}
 
-	// First load the chart without resolving dependencies
-	chart, err := loader.Load(path)
+	// Perform a dry run to get all rendered templates with dependencies resolved
+	rendered, err := DryRunHelmChart(chart, kubeVersion)
 	if err != nil {
 		return ErrLoadHelmChart(err, path)
 	}
 
+	// Split into individual documents and strip nil-value artifacts per
+	// document instead of regexing the whole rendered buffer.
+	manifests, err := SplitManifests(bytes.NewReader(rendered))
+	if err != nil {
+		return ErrLoadHelmChart(err, path)
+	}
+
+	for _, m := range manifests {
+		if err := writeToWriter(w, m.Content); err != nil {
+			return fmt.Errorf("Failed to write manifests to writer: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// loadHelmChartResolvingDeps loads the chart at path, resolving and
+// downloading any dependencies declared in Chart.yaml via dm before
+// returning it. dm.ChartPath must already be set to path.
+func loadHelmChartResolvingDeps(path string, dm *downloader.Manager) (*chart.Chart, error) {
+	// First load the chart without resolving dependencies
+	c, err := loader.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if the chart has dependencies and resolve them
-	if len(chart.Metadata.Dependencies) > 0 {
+	if len(c.Metadata.Dependencies) > 0 {
 		// Update/download all dependencies - this will fetch and process all dependencies
 		// including partials and any other charts specified in Chart.yaml
-		err = dm.Update()
-		if err != nil {
+		if err := dm.Update(); err != nil {
 			// TODO: fail forward
-			return ErrLoadHelmChart(fmt.Errorf("Failed to download Helm chart dependencies: %v", err), path)
+			return nil, fmt.Errorf("Failed to download Helm chart dependencies: %v", err)
 		}
 
 		// Reload the chart after dependencies are resolved to include the newly downloaded
 		// dependencies and their templates
-		chart, err = loader.Load(path)
+		c, err = loader.Load(path)
 		if err != nil {
-			return ErrLoadHelmChart(err, path)
+			return nil, err
 		}
 	}
 
-	// Perform a dry run to get all rendered templates with dependencies resolved
-	manifests, err := DryRunHelmChart(chart, kubeVersion)
-	if err != nil {
-		return ErrLoadHelmChart(err, path)
-	}
-
-	// clean up the manifests for any nil values
-	// while rendering if the value.yml is empty the placeholders gets replaced with %s<nil>
-	// we remove them for now
-	manifests = cleanNilValues(manifests)
-
-	if _, err := w.Write(manifests); err != nil {
-		return fmt.Errorf("Failed to write manifests to writer: %v", err)
-	}
-
-	return nil
+	return c, nil
 }
 
 func cleanNilValues(data []byte) []byte {
@@ -0,0 +1,119 @@
+package helm
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/registry"
+)
+
+// LocateOptions customizes how Locate resolves a chart reference.
+type LocateOptions struct {
+	// RepoURL is searched when ref is a bare "name@version" rather than
+	// "repoAlias/name@version".
+	RepoURL string
+	// Version pins a chart version when ref doesn't already encode one.
+	Version string
+	// CacheDir overrides the default XDG cache directory.
+	CacheDir string
+}
+
+var chartRefRegex = regexp.MustCompile(`^([^/@]+)/([^/@]+)@(.+)$`)
+
+// Locate resolves a chart reference to a local path, downloading and
+// caching it under an XDG cache dir if necessary. ref may be a filesystem
+// path, "repoAlias/name@version", an "oci://registry/name:tag" reference, or
+// an "https://.../chart.tgz" URL.
+func Locate(ref string, opts LocateOptions) (string, error) {
+	return locate(ref, opts, VerifyOptions{})
+}
+
+// locate backs both Locate and LocateWithProvenance. When verify.Verify is
+// set, it asks the underlying action.ChartPathOptions to verify (and, for a
+// remote ref, download) the chart's provenance file as part of resolving
+// it, the same way `helm install --verify` does, so the ".prov" file ends
+// up on disk next to the resolved chart path.
+func locate(ref string, opts LocateOptions, verify VerifyOptions) (string, error) {
+	if isLocalChartPath(ref) {
+		if verify.Verify {
+			if _, err := downloader.VerifyChart(ref, verify.KeyringPath); err != nil {
+				return "", ErrLocateChart(err, ref)
+			}
+		}
+		return ref, nil
+	}
+
+	cacheDir := opts.CacheDir
+	if cacheDir == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return "", ErrLocateChart(err, ref)
+		}
+		cacheDir = filepath.Join(dir, "meshkit", "helm-charts")
+	}
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", ErrLocateChart(err, ref)
+	}
+
+	settings := cli.New()
+	settings.RepositoryCache = cacheDir
+
+	chartRef := ref
+	version := opts.Version
+	if match := chartRefRegex.FindStringSubmatch(ref); match != nil {
+		chartRef = match[1] + "/" + match[2]
+		version = match[3]
+	} else if name, v, ok := strings.Cut(ref, "@"); ok && !strings.Contains(ref, "/") {
+		chartRef = name
+		version = v
+	}
+
+	// action.ChartPathOptions.registryClient is unexported, so the OCI
+	// registry client can only be wired in through an action that exposes
+	// SetRegistryClient, such as action.Install.
+	act := action.NewInstall(new(action.Configuration))
+	act.Version = version
+	act.RepoURL = opts.RepoURL
+	act.Verify = verify.Verify
+	act.Keyring = verify.KeyringPath
+
+	if registry.IsOCI(chartRef) {
+		regClient, err := registry.NewClient()
+		if err != nil {
+			return "", ErrLocateChart(err, ref)
+		}
+		act.SetRegistryClient(regClient)
+	}
+
+	path, err := act.ChartPathOptions.LocateChart(chartRef, settings)
+	if err != nil {
+		return "", ErrLocateChart(err, ref)
+	}
+	return path, nil
+}
+
+// isLocalChartPath reports whether ref should be treated as a filesystem
+// path rather than a remote chart reference.
+func isLocalChartPath(ref string) bool {
+	if strings.Contains(ref, "://") || chartRefRegex.MatchString(ref) {
+		return false
+	}
+	_, err := os.Stat(ref)
+	return err == nil
+}
+
+// ConvertChartRefToK8sManifest resolves ref via Locate and renders it the
+// same way ConvertToK8sManifest renders a chart on disk.
+func ConvertChartRefToK8sManifest(ref, kubeVersion string, w io.Writer, locateOpts LocateOptions) error {
+	path, err := Locate(ref, locateOpts)
+	if err != nil {
+		return err
+	}
+	return ConvertToK8sManifest(path, kubeVersion, w)
+}
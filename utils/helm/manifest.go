@@ -0,0 +1,195 @@
+package helm
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/downloader"
+	"sigs.k8s.io/yaml"
+)
+
+// nilArtifact is the literal string Helm's text/template leaves in rendered
+// output when a values.yaml lookup resolves to nil.
+const nilArtifact = "%!s(<nil>)"
+
+// RenderedManifest is a single Kubernetes manifest document extracted from
+// the concatenated YAML output of DryRunHelmChart/LoadHelmChart, together
+// with the template file it was rendered from.
+type RenderedManifest struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	// Source is the chart-relative template path Helm records in the
+	// "# Source: ..." comment above each rendered document.
+	Source  string
+	Content []byte
+}
+
+// Key uniquely identifies a RenderedManifest within a single rendered chart,
+// the same way Kubernetes itself disambiguates resources.
+func (m RenderedManifest) Key() string {
+	return fmt.Sprintf("%s/%s/%s/%s", m.APIVersion, m.Kind, m.Namespace, m.Name)
+}
+
+var sourceCommentRegex = regexp.MustCompile(`(?m)^#\s*Source:\s*(\S+)\s*$`)
+
+// nilValueRegex matches nilArtifact standing alone as a mapping value or list
+// item. Unquoted it is not valid YAML (a plain scalar cannot start with '%'),
+// so it is quoted before decoding and then removed by stripNilArtifacts.
+var nilValueRegex = regexp.MustCompile(`(?m)(^\s*-\s+|:[ \t]+)%!s\(<nil>\)[ \t]*$`)
+
+// SplitManifests parses the concatenated YAML output of DryRunHelmChart into
+// individual RenderedManifest documents. Documents that don't carry a "kind"
+// (e.g. stray NOTES.txt fragments) are skipped.
+func SplitManifests(r io.Reader) ([]RenderedManifest, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	var manifests []RenderedManifest
+	var doc strings.Builder
+
+	flush := func() error {
+		raw := strings.TrimSpace(doc.String())
+		doc.Reset()
+		if raw == "" {
+			return nil
+		}
+		m, err := parseRenderedManifest(raw)
+		if err != nil {
+			return err
+		}
+		if m != nil {
+			manifests = append(manifests, *m)
+		}
+		return nil
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "---" {
+			if err := flush(); err != nil {
+				return nil, ErrSplitManifests(err)
+			}
+			continue
+		}
+		doc.WriteString(line)
+		doc.WriteString("\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, ErrSplitManifests(err)
+	}
+	if err := flush(); err != nil {
+		return nil, ErrSplitManifests(err)
+	}
+
+	return manifests, nil
+}
+
+func parseRenderedManifest(raw string) (*RenderedManifest, error) {
+	var root interface{}
+	quoted := nilValueRegex.ReplaceAllString(raw, `${1}"`+nilArtifact+`"`)
+	if err := yaml.Unmarshal([]byte(quoted), &root); err != nil {
+		return nil, err
+	}
+	obj, ok := root.(map[string]interface{})
+	if !ok {
+		// Not a mapping at all, e.g. a stray NOTES.txt fragment rendered
+		// between "---" separators; nothing to extract.
+		return nil, nil
+	}
+	kind, _ := obj["kind"].(string)
+	if kind == "" {
+		return nil, nil
+	}
+	apiVersion, _ := obj["apiVersion"].(string)
+	name, namespace := "", ""
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		name, _ = metadata["name"].(string)
+		namespace, _ = metadata["namespace"].(string)
+	}
+
+	content, err := yaml.Marshal(stripNilArtifacts(obj))
+	if err != nil {
+		return nil, err
+	}
+
+	source := ""
+	if match := sourceCommentRegex.FindStringSubmatch(raw); match != nil {
+		source = match[1]
+	}
+
+	return &RenderedManifest{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Namespace:  namespace,
+		Name:       name,
+		Source:     source,
+		Content:    content,
+	}, nil
+}
+
+// stripNilArtifacts walks a decoded manifest and removes the nilArtifact
+// Helm leaves behind for unset values, operating on the parsed structure
+// instead of pattern-matching the rendered bytes.
+func stripNilArtifacts(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			if s, ok := val.(string); ok && s == nilArtifact {
+				continue
+			}
+			out[k] = stripNilArtifacts(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok && s == nilArtifact {
+				out = append(out, map[string]interface{}{})
+				continue
+			}
+			out = append(out, stripNilArtifacts(item))
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// WalkManifests renders the chart at chartPath for kubeVersion and invokes fn
+// once per resulting RenderedManifest, so callers can filter CRDs, rewrite
+// namespaces, or inject labels one resource at a time. Like LoadHelmChart,
+// it never runs cleanNilValues over the raw rendered buffer; nil artifacts
+// are stripped per-document in parseRenderedManifest instead.
+func WalkManifests(chartPath, kubeVersion string, fn func(RenderedManifest) error) error {
+	var buf bytes.Buffer
+	dm := downloader.Manager{Out: &buf, ChartPath: chartPath}
+
+	c, err := loadHelmChartResolvingDeps(chartPath, &dm)
+	if err != nil {
+		return ErrWalkManifests(err, chartPath)
+	}
+
+	rendered, err := DryRunHelmChart(c, kubeVersion)
+	if err != nil {
+		return ErrWalkManifests(err, chartPath)
+	}
+
+	manifests, err := SplitManifests(bytes.NewReader(rendered))
+	if err != nil {
+		return ErrWalkManifests(err, chartPath)
+	}
+
+	for _, m := range manifests {
+		if err := fn(m); err != nil {
+			return ErrWalkManifests(err, chartPath)
+		}
+	}
+	return nil
+}
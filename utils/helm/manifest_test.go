@@ -0,0 +1,72 @@
+package helm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitManifests(t *testing.T) {
+	input := `# Source: mychart/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-config
+  namespace: default
+data:
+  foo: bar
+---
+# Source: mychart/templates/notes.txt
+This is not a manifest
+---
+# Source: mychart/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: my-service
+`
+
+	manifests, err := SplitManifests(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("SplitManifests returned error: %v", err)
+	}
+
+	if len(manifests) != 2 {
+		t.Fatalf("expected 2 manifests, got %d", len(manifests))
+	}
+
+	if got := manifests[0].Key(); got != "v1/ConfigMap/default/my-config" {
+		t.Errorf("unexpected key for first manifest: %s", got)
+	}
+	if manifests[0].Source != "mychart/templates/configmap.yaml" {
+		t.Errorf("unexpected source: %s", manifests[0].Source)
+	}
+
+	if manifests[1].Kind != "Service" || manifests[1].Name != "my-service" {
+		t.Errorf("unexpected second manifest: %+v", manifests[1])
+	}
+}
+
+func TestStripNilArtifacts(t *testing.T) {
+	in := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": nilArtifact,
+			"selector": map[string]interface{}{"app": "x"},
+		},
+		"list": []interface{}{"a", nilArtifact},
+	}
+
+	out, ok := stripNilArtifacts(in).(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected map[string]interface{}, got %T", out)
+	}
+
+	spec := out["spec"].(map[string]interface{})
+	if _, exists := spec["replicas"]; exists {
+		t.Errorf("expected \"replicas\" to be stripped, found %v", spec["replicas"])
+	}
+
+	list := out["list"].([]interface{})
+	if _, ok := list[1].(map[string]interface{}); !ok {
+		t.Errorf("expected nil artifact in list to become an empty map, got %T", list[1])
+	}
+}
@@ -0,0 +1,106 @@
+package helm
+
+import (
+	"fmt"
+	"io"
+
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/provenance"
+)
+
+// Provenance is the verified signer of a chart downloaded with provenance
+// verification enabled.
+type Provenance struct {
+	Signer      string
+	Fingerprint string
+	SHA256      string
+}
+
+// VerifyOptions configures provenance verification for chart downloads.
+type VerifyOptions struct {
+	// Verify turns on checking the chart's detached OpenPGP signature
+	// (its "*.prov" file) against KeyringPath.
+	Verify bool
+	// KeyringPath is required when Verify is true.
+	KeyringPath string
+}
+
+// LoadHelmChartWithVerify behaves like LoadHelmChart, but when opts.Verify
+// is set, every downloaded chart dependency must carry a valid provenance
+// signature against opts.KeyringPath or the load fails.
+func LoadHelmChartWithVerify(path string, w io.Writer, kubeVersion string, opts VerifyOptions) error {
+	dm := downloader.Manager{
+		Out:       w,
+		ChartPath: path,
+	}
+	if opts.Verify {
+		dm.Verify = downloader.VerifyAlways
+		dm.Keyring = opts.KeyringPath
+	}
+
+	c, err := loadHelmChartResolvingDeps(path, &dm)
+	if err != nil {
+		return ErrLoadHelmChart(err, path)
+	}
+
+	manifests, err := DryRunHelmChart(c, kubeVersion)
+	if err != nil {
+		return ErrLoadHelmChart(err, path)
+	}
+	manifests = cleanNilValues(manifests)
+
+	if _, err := w.Write(manifests); err != nil {
+		return ErrLoadHelmChart(err, path)
+	}
+	return nil
+}
+
+// LocateWithProvenance behaves like Locate, but when verify.Verify is set,
+// it additionally downloads the chart's "*.prov" file and verifies its
+// detached OpenPGP signature against verify.KeyringPath, returning the
+// signer's identity alongside the resolved chart path.
+func LocateWithProvenance(ref string, opts LocateOptions, verify VerifyOptions) (string, *Provenance, error) {
+	chartPath, err := locate(ref, opts, verify)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !verify.Verify {
+		return chartPath, nil, nil
+	}
+
+	prov, err := verifyChartProvenance(chartPath, verify.KeyringPath)
+	if err != nil {
+		return "", nil, ErrVerifyChartProvenance(err, ref)
+	}
+
+	return chartPath, prov, nil
+}
+
+// verifyChartProvenance verifies chartPath's detached OpenPGP signature
+// against keyringPath, using the "<chartPath>.prov" file that locate
+// already fetched (for a remote ref) or that was sitting next to the chart
+// (for a local one).
+func verifyChartProvenance(chartPath, keyringPath string) (*Provenance, error) {
+	signatory, err := provenance.NewFromKeyring(keyringPath, "")
+	if err != nil {
+		return nil, err
+	}
+
+	verification, err := signatory.Verify(chartPath, chartPath+".prov")
+	if err != nil {
+		return nil, err
+	}
+
+	signer := ""
+	for identity := range verification.SignedBy.Identities {
+		signer = identity
+		break
+	}
+
+	return &Provenance{
+		Signer:      signer,
+		Fingerprint: fmt.Sprintf("%X", verification.SignedBy.PrimaryKey.Fingerprint),
+		SHA256:      verification.FileHash,
+	}, nil
+}